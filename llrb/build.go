@@ -0,0 +1,116 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+// BuildSorted constructs a new, perfectly balanced tree from items in
+// O(n) time. items must already be sorted in ascending order; BuildSorted
+// does not check this. It is the bulk-load counterpart to the O(n log n)
+// loop implied by ReplaceOrInsertBulk, meant for loading a snapshot or
+// importing from another ordered container.
+func BuildSorted(items []Item) *LLRB {
+	t := New()
+	t.LoadSorted(items)
+	return t
+}
+
+// LoadSorted replaces the tree's contents with a new, perfectly balanced
+// tree built from items in O(n) time. items must already be sorted in
+// ascending order; LoadSorted does not check this.
+func (t *LLRB) LoadSorted(items []Item) {
+	t.root = build23(items, depthFor(len(items)))
+	t.count = len(items)
+	if Debug {
+		checkInvariants(t.root)
+	}
+}
+
+// depthFor returns the smallest 2-3-tree depth whose maximum capacity
+// (max23Cap) can hold n items.
+func depthFor(n int) int {
+	d := 0
+	for max23Cap(d) < n {
+		d++
+	}
+	return d
+}
+
+// min23Cap and max23Cap are the fewest and most items a 2-3 subtree of
+// the given depth can hold: a depth of all 2-nodes holds 2^depth-1
+// items, one of all 3-nodes holds 3^depth-1.
+func min23Cap(depth int) int {
+	if depth == 0 {
+		return 0
+	}
+	return 1<<uint(depth) - 1
+}
+
+func max23Cap(depth int) int {
+	if depth == 0 {
+		return 0
+	}
+	c := 1
+	for i := 0; i < depth; i++ {
+		c *= 3
+	}
+	return c - 1
+}
+
+// splitEven divides n into k parts, each within one of another, summing
+// to n.
+func splitEven(n, k int) []int {
+	base, extra := n/k, n%k
+	parts := make([]int, k)
+	for i := range parts {
+		parts[i] = base
+		if i < extra {
+			parts[i]++
+		}
+	}
+	return parts
+}
+
+// build23 builds a perfectly balanced subtree of 2-3-tree depth depth
+// from items, choosing at each node whether to lay down a 2-node (one
+// item, two depth-1 children) or, once that no longer has room, a
+// 3-node (two items, three depth-1 children represented LLRB-style as a
+// black node with a red left child). The result always has a black
+// root, so callers may attach it as either child of their own node
+// without creating two reds in a row.
+func build23(items []Item, depth int) *Node {
+	n := len(items)
+	if n == 0 {
+		return nil
+	}
+
+	childMin, childMax := min23Cap(depth-1), max23Cap(depth-1)
+
+	if rem := n - 1; rem >= 2*childMin && rem <= 2*childMax {
+		sizes := splitEven(rem, 2)
+		left, right := sizes[0], sizes[1]
+
+		h := newNode(items[left])
+		h.Black = true
+		h.Left = build23(items[:left], depth-1)
+		h.Right = build23(items[left+1:], depth-1)
+		h.NLeft, h.NRight = left, right
+		return h
+	}
+
+	rem := n - 2
+	sizes := splitEven(rem, 3)
+	left, mid, right := sizes[0], sizes[1], sizes[2]
+
+	red := newNode(items[left])
+	red.Left = build23(items[:left], depth-1)
+	red.Right = build23(items[left+1:left+1+mid], depth-1)
+	red.NLeft, red.NRight = left, mid
+
+	h := newNode(items[left+1+mid])
+	h.Black = true
+	h.Left = red
+	h.Right = build23(items[left+2+mid:], depth-1)
+	h.NLeft, h.NRight = left+1+mid, right
+	return h
+}
@@ -0,0 +1,103 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import (
+	"testing"
+)
+
+func inorderItems(h *Node) []int {
+	if h == nil {
+		return nil
+	}
+	out := inorderItems(h.Left)
+	out = append(out, int(h.Item.(Int)))
+	out = append(out, inorderItems(h.Right)...)
+	return out
+}
+
+// maxDepth returns the number of nodes on the longest root-to-nil path.
+func maxDepth(h *Node) int {
+	if h == nil {
+		return 0
+	}
+	l, r := maxDepth(h.Left), maxDepth(h.Right)
+	if l > r {
+		return l + 1
+	}
+	return r + 1
+}
+
+// TestBuildSorted checks that BuildSorted produces a tree with the
+// right contents, valid NLeft/NRight bookkeeping, and height within the
+// O(log n) bound expected of a balanced build, for a range of sizes
+// including the empty tree and sizes that straddle a 2-3 depth
+// boundary.
+func TestBuildSorted(t *testing.T) {
+	for n := 0; n <= 200; n++ {
+		items := make([]Item, n)
+		for i := range items {
+			items[i] = Int(i)
+		}
+
+		tree := BuildSorted(items)
+
+		if tree.Len() != n {
+			t.Fatalf("n=%d: Len() = %d, want %d", n, tree.Len(), n)
+		}
+		checkInvariants(tree.Root())
+
+		got := inorderItems(tree.Root())
+		if len(got) != n {
+			t.Fatalf("n=%d: in-order length = %d, want %d", n, len(got), n)
+		}
+		for i, v := range got {
+			if v != i {
+				t.Fatalf("n=%d: in-order[%d] = %d, want %d", n, i, v, i)
+			}
+		}
+
+		if d := maxDepth(tree.Root()); n > 0 {
+			limit := 1
+			for (1 << limit) < n+1 {
+				limit++
+			}
+			limit *= 2 // generous O(log n) bound, not a tight one
+			if d > limit {
+				t.Fatalf("n=%d: tree depth %d exceeds O(log n) bound %d", n, d, limit)
+			}
+		}
+
+		for i := 0; i < n; i++ {
+			if !tree.Has(Int(i)) {
+				t.Fatalf("n=%d: Has(%d) = false, want true", n, i)
+			}
+		}
+	}
+}
+
+// TestLoadSorted checks that LoadSorted discards whatever the tree held
+// before.
+func TestLoadSorted(t *testing.T) {
+	tree := New()
+	tree.ReplaceOrInsert(Int(1000))
+	tree.ReplaceOrInsert(Int(2000))
+
+	items := []Item{Int(1), Int(2), Int(3), Int(4), Int(5)}
+	tree.LoadSorted(items)
+
+	if tree.Has(Int(1000)) || tree.Has(Int(2000)) {
+		t.Fatalf("LoadSorted did not discard the previous contents")
+	}
+	if tree.Len() != len(items) {
+		t.Fatalf("Len() = %d, want %d", tree.Len(), len(items))
+	}
+	checkInvariants(tree.Root())
+	for _, it := range items {
+		if !tree.Has(it) {
+			t.Fatalf("Has(%v) = false, want true", it)
+		}
+	}
+}
@@ -0,0 +1,447 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package generic provides a type-parameterized Left-Leaning Red-Black
+// (LLRB) tree that mirrors the llrb package but stores values directly,
+// extracting their ordering key via a user-supplied function. Unlike the
+// interface-based LLRB, which boxes every item as an llrb.Item and pays
+// for a dynamic Less dispatch on every comparison, Tree compares keys
+// through a plain function value and stores V without a wrapper type.
+package generic
+
+// LessFunc reports whether a orders before b.
+type LessFunc[K any] func(a, b K) bool
+
+// Tree is a Left-Leaning Red-Black (LLRB) implementation of 2-3 trees,
+// parameterized over a key type K and a value type V. Values are ordered
+// by the key that KeyFn extracts from them.
+type Tree[K any, V any] struct {
+	count int
+	root  *Node[K, V]
+	keyFn func(V) K
+	less  LessFunc[K]
+}
+
+// Node is a node of the tree.
+type Node[K any, V any] struct {
+	Key           K
+	Val           V
+	Left, Right   *Node[K, V] // Pointers to left and right child nodes
+	NLeft, NRight int
+	Black         bool // If set, the color of the link (incoming from the parent) is black
+	// In the LLRB, new nodes are always red, hence the zero-value for node
+}
+
+// New allocates a new tree. keyFn extracts the ordering key from a value
+// and less reports whether one key orders before another.
+func New[K any, V any](keyFn func(V) K, less LessFunc[K]) *Tree[K, V] {
+	return &Tree[K, V]{keyFn: keyFn, less: less}
+}
+
+// SetRoot sets the root node of the tree.
+// It is intended to be used by functions that deserialize the tree.
+func (t *Tree[K, V]) SetRoot(r *Node[K, V]) {
+	t.root = r
+}
+
+// Root returns the root node of the tree.
+// It is intended to be used by functions that serialize the tree.
+func (t *Tree[K, V]) Root() *Node[K, V] {
+	return t.root
+}
+
+// Len returns the number of nodes in the tree.
+func (t *Tree[K, V]) Len() int { return t.count }
+
+// Has returns true if the tree contains a value whose key orders the
+// same as key.
+func (t *Tree[K, V]) Has(key K) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// Get retrieves a value from the tree whose key orders the same as key.
+func (t *Tree[K, V]) Get(key K) (V, bool) {
+	h := t.root
+	for h != nil {
+		switch {
+		case t.less(key, h.Key):
+			h = h.Left
+		case t.less(h.Key, key):
+			h = h.Right
+		default:
+			return h.Val, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Min returns the value with the minimum key in the tree.
+func (t *Tree[K, V]) Min() (V, bool) {
+	h := t.root
+	if h == nil {
+		var zero V
+		return zero, false
+	}
+	for h.Left != nil {
+		h = h.Left
+	}
+	return h.Val, true
+}
+
+// Max returns the value with the maximum key in the tree.
+func (t *Tree[K, V]) Max() (V, bool) {
+	h := t.root
+	if h == nil {
+		var zero V
+		return zero, false
+	}
+	for h.Right != nil {
+		h = h.Right
+	}
+	return h.Val, true
+}
+
+// ReplaceOrInsert inserts val into the tree. If an existing value has the
+// same key, it is removed from the tree and returned. Returns the
+// replaced value, if any, and the inserted value's position from the
+// smallest value in the tree.
+func (t *Tree[K, V]) ReplaceOrInsert(val V) (replaced V, replacedOK bool, pos int) {
+	key := t.keyFn(val)
+	t.root, replaced, replacedOK, pos = t.replaceOrInsert(t.root, key, val, 0)
+	t.root.Black = true
+	if !replacedOK {
+		t.count++
+	}
+	return replaced, replacedOK, pos
+}
+
+func (t *Tree[K, V]) replaceOrInsert(h *Node[K, V], key K, val V, n int) (_ *Node[K, V], replaced V, replacedOK bool, pos int) {
+	if h == nil {
+		return newNode[K, V](key, val), replaced, false, n
+	}
+
+	if t.less(key, h.Key) {
+		h.Left, replaced, replacedOK, pos = t.replaceOrInsert(h.Left, key, val, n)
+		if !replacedOK {
+			h.NLeft++
+		}
+	} else if t.less(h.Key, key) {
+		h.Right, replaced, replacedOK, pos = t.replaceOrInsert(h.Right, key, val, n+1+h.NLeft)
+		if !replacedOK {
+			h.NRight++
+		}
+	} else {
+		replaced, replacedOK, pos = h.Val, true, n
+		h.Key, h.Val = key, val
+	}
+
+	h = t.walkUpRot23(h)
+
+	return h, replaced, replacedOK, pos
+}
+
+// InsertNoReplace inserts val into the tree. If an existing value has the
+// same key, both values remain in the tree. Returns the position of the
+// inserted value from the smallest value in the tree.
+func (t *Tree[K, V]) InsertNoReplace(val V) int {
+	key := t.keyFn(val)
+	var pos int
+	t.root, pos = t.insertNoReplace(t.root, key, val, 0)
+	t.root.Black = true
+	t.count++
+	return pos
+}
+
+func (t *Tree[K, V]) insertNoReplace(h *Node[K, V], key K, val V, n int) (*Node[K, V], int) {
+	if h == nil {
+		return newNode[K, V](key, val), n
+	}
+
+	var pos int
+	if t.less(key, h.Key) {
+		h.Left, pos = t.insertNoReplace(h.Left, key, val, n)
+		h.NLeft++
+	} else {
+		h.Right, pos = t.insertNoReplace(h.Right, key, val, n+1+h.NLeft)
+		h.NRight++
+	}
+
+	return t.walkUpRot23(h), pos
+}
+
+// Rotation driver routines for 2-3 algorithm
+
+func (t *Tree[K, V]) walkUpRot23(h *Node[K, V]) *Node[K, V] {
+	if isRed(h.Right) && !isRed(h.Left) {
+		h = rotateLeft(h)
+	}
+
+	if isRed(h.Left) && isRed(h.Left.Left) {
+		h = rotateRight(h)
+	}
+
+	if isRed(h.Left) && isRed(h.Right) {
+		flip(h)
+	}
+
+	return h
+}
+
+// DeleteMin deletes the value with the minimum key in the tree and
+// returns it.
+func (t *Tree[K, V]) DeleteMin() (V, bool) {
+	var deleted V
+	var ok bool
+	t.root, deleted, ok = deleteMin(t.root)
+	if t.root != nil {
+		t.root.Black = true
+	}
+	if ok {
+		t.count--
+	}
+	return deleted, ok
+}
+
+func deleteMin[K any, V any](h *Node[K, V]) (*Node[K, V], V, bool) {
+	if h == nil {
+		var zero V
+		return nil, zero, false
+	}
+	if h.Left == nil {
+		return nil, h.Val, true
+	}
+
+	if !isRed(h.Left) && !isRed(h.Left.Left) {
+		h = moveRedLeft(h)
+	}
+
+	var deleted V
+	var ok bool
+	h.Left, deleted, ok = deleteMin(h.Left)
+	if ok {
+		h.NLeft--
+	}
+
+	return fixUp(h), deleted, ok
+}
+
+// DeleteMax deletes the value with the maximum key in the tree and
+// returns it.
+func (t *Tree[K, V]) DeleteMax() (V, bool) {
+	var deleted V
+	var ok bool
+	t.root, deleted, ok = deleteMax(t.root)
+	if t.root != nil {
+		t.root.Black = true
+	}
+	if ok {
+		t.count--
+	}
+	return deleted, ok
+}
+
+func deleteMax[K any, V any](h *Node[K, V]) (*Node[K, V], V, bool) {
+	if h == nil {
+		var zero V
+		return nil, zero, false
+	}
+	if isRed(h.Left) {
+		h = rotateRight(h)
+	}
+	if h.Right == nil {
+		return nil, h.Val, true
+	}
+	if !isRed(h.Right) && !isRed(h.Right.Left) {
+		h = moveRedRight(h)
+	}
+	var deleted V
+	var ok bool
+	h.Right, deleted, ok = deleteMax(h.Right)
+	if ok {
+		h.NRight--
+	}
+
+	return fixUp(h), deleted, ok
+}
+
+// Delete deletes the value whose key equals key. Returns the deleted
+// value, if any matches, and its position from the smallest value in
+// the tree.
+func (t *Tree[K, V]) Delete(key K) (deleted V, deletedOK bool, pos int) {
+	t.root, deleted, deletedOK, pos = t.delete(t.root, key, 0)
+	if t.root != nil {
+		t.root.Black = true
+	}
+	if deletedOK {
+		t.count--
+	}
+	return deleted, deletedOK, pos
+}
+
+func (t *Tree[K, V]) delete(h *Node[K, V], key K, n int) (_ *Node[K, V], deleted V, deletedOK bool, pos int) {
+	if h == nil {
+		return nil, deleted, false, n
+	}
+	if t.less(key, h.Key) {
+		if h.Left == nil { // key not present. Nothing to delete
+			return h, deleted, false, -1
+		}
+		if !isRed(h.Left) && !isRed(h.Left.Left) {
+			h = moveRedLeft(h)
+		}
+		h.Left, deleted, deletedOK, pos = t.delete(h.Left, key, n)
+		if deletedOK {
+			h.NLeft--
+		}
+	} else {
+		if isRed(h.Left) {
+			h = rotateRight(h)
+		}
+		if !t.less(h.Key, key) && h.Right == nil {
+			return nil, h.Val, true, n + h.NLeft
+		}
+		if h.Right != nil && !isRed(h.Right) && !isRed(h.Right.Left) {
+			h = moveRedRight(h)
+		}
+		if !t.less(h.Key, key) {
+			var subDeleted V
+			var subKey K
+			h.Right, subKey, subDeleted, _ = deleteMinKV(h.Right)
+			deleted, deletedOK, pos = h.Val, true, n+h.NLeft
+			h.Key, h.Val = subKey, subDeleted
+		} else {
+			h.Right, deleted, deletedOK, pos = t.delete(h.Right, key, n+1+h.NLeft)
+		}
+		if deletedOK {
+			h.NRight--
+		}
+	}
+
+	return fixUp(h), deleted, deletedOK, pos
+}
+
+// deleteMinKV is deleteMin but also returns the deleted node's key, for
+// use when delete() promotes the in-order successor into h's place.
+func deleteMinKV[K any, V any](h *Node[K, V]) (*Node[K, V], K, V, bool) {
+	if h == nil {
+		var zeroK K
+		var zeroV V
+		return nil, zeroK, zeroV, false
+	}
+	if h.Left == nil {
+		return nil, h.Key, h.Val, true
+	}
+
+	if !isRed(h.Left) && !isRed(h.Left.Left) {
+		h = moveRedLeft(h)
+	}
+
+	var key K
+	var val V
+	var ok bool
+	h.Left, key, val, ok = deleteMinKV(h.Left)
+	if ok {
+		h.NLeft--
+	}
+
+	return fixUp(h), key, val, ok
+}
+
+// Internal node manipulation routines
+
+func newNode[K any, V any](key K, val V) *Node[K, V] {
+	return &Node[K, V]{Key: key, Val: val}
+}
+
+func isRed[K any, V any](h *Node[K, V]) bool {
+	if h == nil {
+		return false
+	}
+	return !h.Black
+}
+
+func rotateLeft[K any, V any](h *Node[K, V]) *Node[K, V] {
+	x := h.Right
+	if x.Black {
+		panic("rotating a black link")
+	}
+	h.Right = x.Left
+	x.Left = h
+	x.Black = h.Black
+	h.Black = false
+
+	h.NRight = h.Right.size()
+	x.NLeft = x.Left.size()
+
+	return x
+}
+
+func rotateRight[K any, V any](h *Node[K, V]) *Node[K, V] {
+	x := h.Left
+	if x.Black {
+		panic("rotating a black link")
+	}
+	h.Left = x.Right
+	x.Right = h
+	x.Black = h.Black
+	h.Black = false
+
+	h.NLeft = h.Left.size()
+	x.NRight = x.Right.size()
+
+	return x
+}
+
+func (h *Node[K, V]) size() int {
+	if h == nil {
+		return 0
+	}
+	return h.NLeft + 1 + h.NRight
+}
+
+// REQUIRE: Left and Right children must be present
+func flip[K any, V any](h *Node[K, V]) {
+	h.Black = !h.Black
+	h.Left.Black = !h.Left.Black
+	h.Right.Black = !h.Right.Black
+}
+
+// REQUIRE: Left and Right children must be present
+func moveRedLeft[K any, V any](h *Node[K, V]) *Node[K, V] {
+	flip(h)
+	if isRed(h.Right.Left) {
+		h.Right = rotateRight(h.Right)
+		h = rotateLeft(h)
+		flip(h)
+	}
+	return h
+}
+
+// REQUIRE: Left and Right children must be present
+func moveRedRight[K any, V any](h *Node[K, V]) *Node[K, V] {
+	flip(h)
+	if isRed(h.Left.Left) {
+		h = rotateRight(h)
+		flip(h)
+	}
+	return h
+}
+
+func fixUp[K any, V any](h *Node[K, V]) *Node[K, V] {
+	if isRed(h.Right) {
+		h = rotateLeft(h)
+	}
+
+	if isRed(h.Left) && isRed(h.Left.Left) {
+		h = rotateRight(h)
+	}
+
+	if isRed(h.Left) && isRed(h.Right) {
+		flip(h)
+	}
+
+	return h
+}
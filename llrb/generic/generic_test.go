@@ -0,0 +1,169 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package generic
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/cabify/GoLLRB/llrb"
+)
+
+func identity(v int) int { return v }
+
+func lessInt(a, b int) bool { return a < b }
+
+// itemInt implements llrb.Item so the benchmarks below can compare
+// against the interface-based tree.
+type itemInt int
+
+func (i itemInt) Less(than llrb.Item) bool {
+	return i < than.(itemInt)
+}
+
+// checkSizes recomputes NLeft/NRight for every node reachable from h and
+// fails the test if the stored counts disagree -- the same invariant
+// llrb.Debug checks for the interface-based tree.
+func checkSizes(t *testing.T, h *Node[int, int]) int {
+	if h == nil {
+		return 0
+	}
+	nleft := checkSizes(t, h.Left)
+	nright := checkSizes(t, h.Right)
+	if h.NLeft != nleft {
+		t.Fatalf("node %d: NLeft = %d, want %d", h.Key, h.NLeft, nleft)
+	}
+	if h.NRight != nright {
+		t.Fatalf("node %d: NRight = %d, want %d", h.Key, h.NRight, nright)
+	}
+	return nleft + 1 + nright
+}
+
+// TestReplaceOrInsertAgainstReference round-trips a randomized sequence
+// of insertions and deletions through Tree, cross-checking Get/Min/Max
+// and the NLeft/NRight bookkeeping against a reference sorted slice.
+func TestReplaceOrInsertAgainstReference(t *testing.T) {
+	tree := New[int, int](identity, lessInt)
+	var ref []int
+	rng := rand.New(rand.NewSource(1))
+
+	insert := func(v int) {
+		var pos int
+		_, _, pos = tree.ReplaceOrInsert(v)
+		idx := sort.SearchInts(ref, v)
+		if idx == len(ref) || ref[idx] != v {
+			ref = append(ref, 0)
+			copy(ref[idx+1:], ref[idx:])
+			ref[idx] = v
+			if pos != idx {
+				t.Fatalf("ReplaceOrInsert(%d) pos = %d, want %d", v, pos, idx)
+			}
+		}
+	}
+	removeMin := func() {
+		if len(ref) == 0 {
+			return
+		}
+		tree.DeleteMin()
+		ref = ref[1:]
+	}
+
+	for i := 0; i < 2000; i++ {
+		switch rng.Intn(3) {
+		case 0, 1:
+			insert(rng.Intn(500))
+		case 2:
+			removeMin()
+		}
+
+		if tree.Len() != len(ref) {
+			t.Fatalf("Len() = %d, want %d", tree.Len(), len(ref))
+		}
+		checkSizes(t, tree.Root())
+
+		if len(ref) > 0 {
+			if got, ok := tree.Min(); !ok || got != ref[0] {
+				t.Fatalf("Min() = (%v, %v), want (%d, true)", got, ok, ref[0])
+			}
+			if got, ok := tree.Max(); !ok || got != ref[len(ref)-1] {
+				t.Fatalf("Max() = (%v, %v), want (%d, true)", got, ok, ref[len(ref)-1])
+			}
+		}
+		for _, v := range ref {
+			if got, ok := tree.Get(v); !ok || got != v {
+				t.Fatalf("Get(%d) = (%v, %v), want (%d, true)", v, got, ok, v)
+			}
+		}
+	}
+}
+
+// buildN returns n pseudo-random, distinct ints.
+func buildN(n int) []int {
+	seen := make(map[int]bool, n)
+	rng := rand.New(rand.NewSource(int64(n)))
+	out := make([]int, 0, n)
+	for len(out) < n {
+		v := rng.Int()
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// BenchmarkGenericReplaceOrInsert measures insertion into Tree, which
+// stores ints directly instead of boxing them as llrb.Item.
+func BenchmarkGenericReplaceOrInsert(b *testing.B) {
+	values := buildN(b.N)
+	tree := New[int, int](identity, lessInt)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for _, v := range values {
+		tree.ReplaceOrInsert(v)
+	}
+}
+
+// BenchmarkItemReplaceOrInsert is the same workload against the
+// interface-based llrb.LLRB, for comparison: every value is boxed as an
+// llrb.Item and each comparison goes through an interface dispatch.
+func BenchmarkItemReplaceOrInsert(b *testing.B) {
+	values := buildN(b.N)
+	tree := llrb.New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for _, v := range values {
+		tree.ReplaceOrInsert(itemInt(v))
+	}
+}
+
+// BenchmarkGenericGet measures lookups against a pre-populated Tree.
+func BenchmarkGenericGet(b *testing.B) {
+	values := buildN(10000)
+	tree := New[int, int](identity, lessInt)
+	for _, v := range values {
+		tree.ReplaceOrInsert(v)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Get(values[i%len(values)])
+	}
+}
+
+// BenchmarkItemGet is the same lookup workload against llrb.LLRB.
+func BenchmarkItemGet(b *testing.B) {
+	values := buildN(10000)
+	tree := llrb.New()
+	for _, v := range values {
+		tree.ReplaceOrInsert(itemInt(v))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Get(itemInt(values[i%len(values)]))
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+// Iter is an in-order cursor over an LLRB tree. Since Node has no parent
+// pointer, a cursor keeps the path from the root to the current node on
+// an explicit stack, sized to the tree's height, and walks it the way a
+// parent pointer would: stepping to the right child and descending its
+// left spine, or popping back up until the node just left was reached
+// via a left link.
+//
+// An Iter is a snapshot of the path to its current position; it is not
+// safe to use after the tree has been mutated.
+type Iter struct {
+	stack []*Node
+	hasLo bool
+	lo    Item
+	hasHi bool
+	hi    Item
+}
+
+// SeekFirst returns an Iter positioned at the minimum element of the
+// tree.
+func (t *LLRB) SeekFirst() *Iter {
+	it := &Iter{}
+	h := t.root
+	for h != nil {
+		it.stack = append(it.stack, h)
+		h = h.Left
+	}
+	return it
+}
+
+// SeekLast returns an Iter positioned at the maximum element of the
+// tree.
+func (t *LLRB) SeekLast() *Iter {
+	it := &Iter{}
+	h := t.root
+	for h != nil {
+		it.stack = append(it.stack, h)
+		h = h.Right
+	}
+	return it
+}
+
+// Seek returns an Iter positioned at the smallest element that is
+// greater than or equal to key. The returned Iter is invalid if no such
+// element exists.
+func (t *LLRB) Seek(key Item) *Iter {
+	return &Iter{stack: seekCeilStack(t.root, key)}
+}
+
+// SeekRange returns an Iter positioned at the smallest element that is
+// greater than or equal to lo; Next stops advancing once it would pass
+// hi, and Prev stops retreating once it would pass lo. The returned
+// Iter is invalid if the range [lo, hi] is empty.
+func (t *LLRB) SeekRange(lo, hi Item) *Iter {
+	it := &Iter{stack: seekCeilStack(t.root, lo), hasLo: true, lo: lo, hasHi: true, hi: hi}
+	if it.Valid() && less(hi, it.Item()) {
+		it.stack = nil
+	}
+	return it
+}
+
+// seekCeilStack descends like Get, recording on the way down the
+// deepest ancestor reached via a left turn -- the ceiling of key -- and
+// returns the root-to-ceiling path. It returns the path from the root
+// to the matching node if key matches a node exactly, and nil if no
+// element is greater than or equal to key.
+func seekCeilStack(root *Node, key Item) []*Node {
+	var stack []*Node
+	ceilIdx := -1
+	h := root
+	for h != nil {
+		stack = append(stack, h)
+		switch {
+		case less(key, h.Item):
+			ceilIdx = len(stack) - 1
+			h = h.Left
+		case less(h.Item, key):
+			h = h.Right
+		default:
+			return stack
+		}
+	}
+	if ceilIdx == -1 {
+		return nil
+	}
+	return stack[:ceilIdx+1]
+}
+
+// Valid reports whether the cursor is positioned at an element.
+func (it *Iter) Valid() bool {
+	return len(it.stack) > 0
+}
+
+// Item returns the element at the cursor's current position, or nil if
+// the cursor is not valid.
+func (it *Iter) Item() Item {
+	if len(it.stack) == 0 {
+		return nil
+	}
+	return it.stack[len(it.stack)-1].Item
+}
+
+// Next advances the cursor to the next element in order and reports
+// whether it remains valid.
+func (it *Iter) Next() bool {
+	if len(it.stack) == 0 {
+		return false
+	}
+	cur := it.stack[len(it.stack)-1]
+	if cur.Right != nil {
+		h := cur.Right
+		for h != nil {
+			it.stack = append(it.stack, h)
+			h = h.Left
+		}
+	} else {
+		for len(it.stack) > 0 {
+			top := it.stack[len(it.stack)-1]
+			it.stack = it.stack[:len(it.stack)-1]
+			if len(it.stack) > 0 && it.stack[len(it.stack)-1].Left == top {
+				break
+			}
+		}
+	}
+	if !it.Valid() {
+		return false
+	}
+	if it.hasHi && less(it.hi, it.Item()) {
+		it.stack = nil
+		return false
+	}
+	return true
+}
+
+// Prev retreats the cursor to the previous element in order and reports
+// whether it remains valid.
+func (it *Iter) Prev() bool {
+	if len(it.stack) == 0 {
+		return false
+	}
+	cur := it.stack[len(it.stack)-1]
+	if cur.Left != nil {
+		h := cur.Left
+		for h != nil {
+			it.stack = append(it.stack, h)
+			h = h.Right
+		}
+	} else {
+		for len(it.stack) > 0 {
+			top := it.stack[len(it.stack)-1]
+			it.stack = it.stack[:len(it.stack)-1]
+			if len(it.stack) > 0 && it.stack[len(it.stack)-1].Right == top {
+				break
+			}
+		}
+	}
+	if !it.Valid() {
+		return false
+	}
+	if it.hasLo && less(it.Item(), it.lo) {
+		it.stack = nil
+		return false
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import "testing"
+
+func buildIterTree(values ...int) *LLRB {
+	t := New()
+	for _, v := range values {
+		t.ReplaceOrInsert(Int(v))
+	}
+	return t
+}
+
+func TestSeekFirstLastNext(t *testing.T) {
+	tree := buildIterTree(30, 10, 50, 20, 40)
+
+	var got []int
+	for it := tree.SeekFirst(); it.Valid(); it.Next() {
+		got = append(got, int(it.Item().(Int)))
+	}
+	want := []int{10, 20, 30, 40, 50}
+	if !equalInts(got, want) {
+		t.Fatalf("forward walk = %v, want %v", got, want)
+	}
+
+	got = nil
+	for it := tree.SeekLast(); it.Valid(); it.Prev() {
+		got = append(got, int(it.Item().(Int)))
+	}
+	want = []int{50, 40, 30, 20, 10}
+	if !equalInts(got, want) {
+		t.Fatalf("backward walk = %v, want %v", got, want)
+	}
+}
+
+func TestSeek(t *testing.T) {
+	tree := buildIterTree(10, 20, 30, 40, 50)
+
+	it := tree.Seek(Int(25))
+	if !it.Valid() || it.Item() != Int(30) {
+		t.Fatalf("Seek(25) = %v, want 30", it.Item())
+	}
+
+	it = tree.Seek(Int(60))
+	if it.Valid() {
+		t.Fatalf("Seek(60) = %v, want invalid", it.Item())
+	}
+}
+
+// TestSeekRangePrevStopsAtLo is the precise repro from the review: after
+// landing on lo via SeekRange, Prev must not walk past lo.
+func TestSeekRangePrevStopsAtLo(t *testing.T) {
+	tree := buildIterTree(10, 20, 30, 40, 50)
+
+	it := tree.SeekRange(Int(20), Int(40))
+	if !it.Valid() || it.Item() != Int(20) {
+		t.Fatalf("SeekRange(20, 40) starts at %v, want 20", it.Item())
+	}
+	if it.Prev() {
+		t.Fatalf("Prev() past lo returned valid, positioned at %v", it.Item())
+	}
+	if it.Valid() {
+		t.Fatalf("Iter should be invalid after stepping past lo")
+	}
+}
+
+func TestSeekRangeNextStopsAtHi(t *testing.T) {
+	tree := buildIterTree(10, 20, 30, 40, 50)
+
+	it := tree.SeekRange(Int(20), Int(40))
+	var got []int
+	for it.Valid() {
+		got = append(got, int(it.Item().(Int)))
+		it.Next()
+	}
+	want := []int{20, 30, 40}
+	if !equalInts(got, want) {
+		t.Fatalf("SeekRange(20, 40) walk = %v, want %v", got, want)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
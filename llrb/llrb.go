@@ -79,6 +79,13 @@ func New() *LLRB {
 	return &LLRB{}
 }
 
+// Debug, when set to true, makes every mutating operation recompute each
+// visited node's subtree sizes from scratch and compare them against
+// NLeft/NRight, panicking on mismatch. It is meant for tests and
+// debugging sessions, not production use, since it turns every update
+// into an O(n) operation.
+var Debug = false
+
 // SetRoot sets the root node of the tree.
 // It is intended to be used by functions that deserialize the tree.
 func (t *LLRB) SetRoot(r *Node) {
@@ -165,6 +172,9 @@ func (t *LLRB) ReplaceOrInsert(item Item) (Item, int) {
 	if replaced == nil {
 		t.count++
 	}
+	if Debug {
+		checkInvariants(t.root)
+	}
 	return replaced, pos
 }
 
@@ -177,10 +187,16 @@ func (t *LLRB) replaceOrInsert(h *Node, item Item, n int) (*Node, Item, int) {
 
 	var replaced Item
 	var pos int
-	if less(item, h.Item) { // BUG
+	if less(item, h.Item) {
 		h.Left, replaced, pos = t.replaceOrInsert(h.Left, item, n)
+		if replaced == nil {
+			h.NLeft++
+		}
 	} else if less(h.Item, item) {
 		h.Right, replaced, pos = t.replaceOrInsert(h.Right, item, n+1+h.NLeft)
+		if replaced == nil {
+			h.NRight++
+		}
 	} else {
 		replaced, h.Item, pos = h.Item, item, n
 	}
@@ -201,6 +217,9 @@ func (t *LLRB) InsertNoReplace(item Item) int {
 	t.root, pos = t.insertNoReplace(t.root, item, 0)
 	t.root.Black = true
 	t.count++
+	if Debug {
+		checkInvariants(t.root)
+	}
 	return pos
 }
 
@@ -276,6 +295,9 @@ func (t *LLRB) DeleteMin() Item {
 	if deleted != nil {
 		t.count--
 	}
+	if Debug {
+		checkInvariants(t.root)
+	}
 	return deleted
 }
 
@@ -312,6 +334,9 @@ func (t *LLRB) DeleteMax() Item {
 	if deleted != nil {
 		t.count--
 	}
+	if Debug {
+		checkInvariants(t.root)
+	}
 	return deleted
 }
 
@@ -348,6 +373,9 @@ func (t *LLRB) Delete(key Item) (deleted Item, pos int) {
 	if deleted != nil {
 		t.count--
 	}
+	if Debug {
+		checkInvariants(t.root)
+	}
 	return deleted, pos
 }
 
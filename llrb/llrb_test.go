@@ -0,0 +1,95 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+type Int int
+
+func (x Int) Less(than Item) bool {
+	return x < than.(Int)
+}
+
+// TestRankSelectAgainstReference drives ReplaceOrInsert/DeleteMin through
+// a randomized sequence of insertions and deletions with Debug enabled,
+// so that any NLeft/NRight bookkeeping mistake panics immediately via
+// the checkInvariants call both methods now make, and cross-checks
+// Rank/Select against a plain sorted slice kept in parallel.
+func TestRankSelectAgainstReference(t *testing.T) {
+	old := Debug
+	Debug = true
+	defer func() { Debug = old }()
+
+	tree := New()
+	var ref []int
+	rng := rand.New(rand.NewSource(1))
+
+	insert := func(v int) {
+		tree.ReplaceOrInsert(Int(v))
+		i := sort.SearchInts(ref, v)
+		if i == len(ref) || ref[i] != v {
+			ref = append(ref, 0)
+			copy(ref[i+1:], ref[i:])
+			ref[i] = v
+		}
+	}
+	removeMin := func() {
+		if len(ref) == 0 {
+			return
+		}
+		tree.DeleteMin()
+		ref = ref[1:]
+	}
+
+	for i := 0; i < 2000; i++ {
+		switch rng.Intn(3) {
+		case 0, 1:
+			insert(rng.Intn(500))
+		case 2:
+			removeMin()
+		}
+
+		if tree.Len() != len(ref) {
+			t.Fatalf("Len() = %d, want %d", tree.Len(), len(ref))
+		}
+		for rank, v := range ref {
+			if got := tree.Rank(Int(v)); got != rank {
+				t.Fatalf("Rank(%d) = %d, want %d", v, got, rank)
+			}
+			if got := tree.Select(rank); got != Int(v) {
+				t.Fatalf("Select(%d) = %v, want %d", rank, got, v)
+			}
+		}
+	}
+}
+
+// TestDeleteAt checks that DeleteAt(k) removes the kth smallest element
+// and keeps the rest of the ordering intact.
+func TestDeleteAt(t *testing.T) {
+	old := Debug
+	Debug = true
+	defer func() { Debug = old }()
+
+	tree := New()
+	ref := []int{5, 10, 15, 20, 25, 30}
+	for _, v := range ref {
+		tree.ReplaceOrInsert(Int(v))
+	}
+
+	deleted, pos := tree.DeleteAt(2)
+	if deleted != Int(15) || pos != 2 {
+		t.Fatalf("DeleteAt(2) = (%v, %d), want (15, 2)", deleted, pos)
+	}
+	ref = append(ref[:2], ref[3:]...)
+	for rank, v := range ref {
+		if got := tree.Select(rank); got != Int(v) {
+			t.Fatalf("Select(%d) = %v, want %d", rank, got, v)
+		}
+	}
+}
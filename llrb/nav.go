@@ -0,0 +1,75 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+// Floor returns the greatest element in the tree that is less than or
+// equal to key, or nil if no such element exists.
+func (t *LLRB) Floor(key Item) Item {
+	var floor Item
+	h := t.root
+	for h != nil {
+		switch {
+		case less(key, h.Item):
+			h = h.Left
+		case less(h.Item, key):
+			floor = h.Item
+			h = h.Right
+		default:
+			return h.Item
+		}
+	}
+	return floor
+}
+
+// Ceiling returns the smallest element in the tree that is greater than
+// or equal to key, or nil if no such element exists.
+func (t *LLRB) Ceiling(key Item) Item {
+	var ceiling Item
+	h := t.root
+	for h != nil {
+		switch {
+		case less(h.Item, key):
+			h = h.Right
+		case less(key, h.Item):
+			ceiling = h.Item
+			h = h.Left
+		default:
+			return h.Item
+		}
+	}
+	return ceiling
+}
+
+// Predecessor returns the greatest element in the tree that is strictly
+// less than key, or nil if no such element exists.
+func (t *LLRB) Predecessor(key Item) Item {
+	var pred Item
+	h := t.root
+	for h != nil {
+		if less(h.Item, key) {
+			pred = h.Item
+			h = h.Right
+		} else {
+			h = h.Left
+		}
+	}
+	return pred
+}
+
+// Successor returns the smallest element in the tree that is strictly
+// greater than key, or nil if no such element exists.
+func (t *LLRB) Successor(key Item) Item {
+	var succ Item
+	h := t.root
+	for h != nil {
+		if less(key, h.Item) {
+			succ = h.Item
+			h = h.Left
+		} else {
+			h = h.Right
+		}
+	}
+	return succ
+}
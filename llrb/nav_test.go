@@ -0,0 +1,81 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import "testing"
+
+func buildNavTree(values ...int) *LLRB {
+	t := New()
+	for _, v := range values {
+		t.ReplaceOrInsert(Int(v))
+	}
+	return t
+}
+
+func TestFloor(t *testing.T) {
+	tree := buildNavTree(10, 20, 30, 40, 50)
+
+	cases := []struct {
+		key  int
+		want Item
+	}{
+		{5, nil},
+		{10, Int(10)},
+		{15, Int(10)},
+		{30, Int(30)},
+		{45, Int(40)},
+		{100, Int(50)},
+	}
+	for _, c := range cases {
+		if got := tree.Floor(Int(c.key)); got != c.want {
+			t.Errorf("Floor(%d) = %v, want %v", c.key, got, c.want)
+		}
+	}
+}
+
+func TestCeiling(t *testing.T) {
+	tree := buildNavTree(10, 20, 30, 40, 50)
+
+	cases := []struct {
+		key  int
+		want Item
+	}{
+		{5, Int(10)},
+		{10, Int(10)},
+		{15, Int(20)},
+		{30, Int(30)},
+		{45, Int(50)},
+		{100, nil},
+	}
+	for _, c := range cases {
+		if got := tree.Ceiling(Int(c.key)); got != c.want {
+			t.Errorf("Ceiling(%d) = %v, want %v", c.key, got, c.want)
+		}
+	}
+}
+
+func TestPredecessorSuccessor(t *testing.T) {
+	tree := buildNavTree(10, 20, 30, 40, 50)
+
+	cases := []struct {
+		key      int
+		wantPred Item
+		wantSucc Item
+	}{
+		{5, nil, Int(10)},
+		{10, nil, Int(20)},
+		{30, Int(20), Int(40)},
+		{50, Int(40), nil},
+		{100, Int(50), nil},
+	}
+	for _, c := range cases {
+		if got := tree.Predecessor(Int(c.key)); got != c.wantPred {
+			t.Errorf("Predecessor(%d) = %v, want %v", c.key, got, c.wantPred)
+		}
+		if got := tree.Successor(Int(c.key)); got != c.wantSucc {
+			t.Errorf("Successor(%d) = %v, want %v", c.key, got, c.wantSucc)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+// Persistent is an applicative (purely functional) variant of LLRB: its
+// mutating operations never modify the receiver in place. Instead they
+// path-copy every *Node visited on the way down and return a new
+// Persistent value whose root may share unvisited subtrees with the
+// original. A Persistent taken by plain value copy therefore remains a
+// valid, immutable snapshot no matter what further updates are applied
+// to the tree it was copied from -- the same "applicative balanced tree"
+// pattern used by the Go compiler's internal abt package.
+//
+// Every *Node reachable from a previously returned Persistent must be
+// treated as immutable by callers: only nodes freshly allocated on the
+// current update's path are ever written to.
+type Persistent struct {
+	root  *Node
+	count int
+}
+
+// Len returns the number of nodes in the tree.
+func (t Persistent) Len() int { return t.count }
+
+// Has returns true if the tree contains an element whose order is the same as that of key.
+func (t Persistent) Has(key Item) bool {
+	return t.Get(key) != nil
+}
+
+// Get retrieves an element from the tree whose order is the same as that of key.
+func (t Persistent) Get(key Item) Item {
+	h := t.root
+	for h != nil {
+		switch {
+		case less(key, h.Item):
+			h = h.Left
+		case less(h.Item, key):
+			h = h.Right
+		default:
+			return h.Item
+		}
+	}
+	return nil
+}
+
+// Min returns the minimum element in the tree.
+func (t Persistent) Min() Item {
+	h := t.root
+	if h == nil {
+		return nil
+	}
+	for h.Left != nil {
+		h = h.Left
+	}
+	return h.Item
+}
+
+// Max returns the maximum element in the tree.
+func (t Persistent) Max() Item {
+	h := t.root
+	if h == nil {
+		return nil
+	}
+	for h.Right != nil {
+		h = h.Right
+	}
+	return h.Item
+}
+
+// cloneNode returns a shallow copy of h, or nil if h is nil. It is the
+// unit of path-copying: every node on an update path is cloned exactly
+// once, before any of its fields are written.
+func cloneNode(h *Node) *Node {
+	if h == nil {
+		return nil
+	}
+	c := *h
+	return &c
+}
+
+// ReplaceOrInsert returns a new tree with item inserted. If an existing
+// element has the same order, it is removed from the returned tree's
+// lineage and returned here. The receiver is left unmodified.
+func (t Persistent) ReplaceOrInsert(item Item) (result Persistent, replaced Item, pos int) {
+	if item == nil {
+		panic("inserting nil item")
+	}
+	result = t
+	result.root, replaced, pos = result.replaceOrInsert(t.root, item, 0)
+	result.root.Black = true
+	if replaced == nil {
+		result.count++
+	}
+	return result, replaced, pos
+}
+
+func (t Persistent) replaceOrInsert(h *Node, item Item, n int) (*Node, Item, int) {
+	if h == nil {
+		return newNode(item), nil, n
+	}
+	h = cloneNode(h)
+
+	var replaced Item
+	var pos int
+	if less(item, h.Item) {
+		h.Left, replaced, pos = t.replaceOrInsert(h.Left, item, n)
+		if replaced == nil {
+			h.NLeft++
+		}
+	} else if less(h.Item, item) {
+		h.Right, replaced, pos = t.replaceOrInsert(h.Right, item, n+1+h.NLeft)
+		if replaced == nil {
+			h.NRight++
+		}
+	} else {
+		replaced, h.Item, pos = h.Item, item, n
+	}
+
+	h = pWalkUpRot23(h)
+
+	return h, replaced, pos
+}
+
+// InsertNoReplace returns a new tree with item inserted. If an existing
+// element has the same order, both elements remain in the returned
+// tree. The receiver is left unmodified.
+func (t Persistent) InsertNoReplace(item Item) (result Persistent, pos int) {
+	if item == nil {
+		panic("inserting nil item")
+	}
+	result = t
+	result.root, pos = result.insertNoReplace(t.root, item, 0)
+	result.root.Black = true
+	result.count++
+	return result, pos
+}
+
+func (t Persistent) insertNoReplace(h *Node, item Item, n int) (*Node, int) {
+	if h == nil {
+		return newNode(item), n
+	}
+	h = cloneNode(h)
+
+	var pos int
+	if less(item, h.Item) {
+		h.Left, pos = t.insertNoReplace(h.Left, item, n)
+		h.NLeft++
+	} else {
+		h.Right, pos = t.insertNoReplace(h.Right, item, n+1+h.NLeft)
+		h.NRight++
+	}
+
+	return pWalkUpRot23(h), pos
+}
+
+func pWalkUpRot23(h *Node) *Node {
+	if isRed(h.Right) && !isRed(h.Left) {
+		h = pRotateLeft(h)
+	}
+
+	if isRed(h.Left) && isRed(h.Left.Left) {
+		h = pRotateRight(h)
+	}
+
+	if isRed(h.Left) && isRed(h.Right) {
+		pFlip(h)
+	}
+
+	return h
+}
+
+// DeleteMin returns a new tree with its minimum element removed, along
+// with the deleted item, if any. The receiver is left unmodified.
+func (t Persistent) DeleteMin() (result Persistent, deleted Item) {
+	result = t
+	result.root, deleted = pDeleteMin(t.root)
+	if result.root != nil {
+		result.root.Black = true
+	}
+	if deleted != nil {
+		result.count--
+	}
+	return result, deleted
+}
+
+func pDeleteMin(h *Node) (*Node, Item) {
+	if h == nil {
+		return nil, nil
+	}
+	h = cloneNode(h)
+	if h.Left == nil {
+		return nil, h.Item
+	}
+
+	if !isRed(h.Left) && !isRed(h.Left.Left) {
+		h = pMoveRedLeft(h)
+	}
+
+	var deleted Item
+	h.Left, deleted = pDeleteMin(h.Left)
+	if deleted != nil {
+		h.NLeft--
+	}
+
+	return pFixUp(h), deleted
+}
+
+// DeleteMax returns a new tree with its maximum element removed, along
+// with the deleted item, if any. The receiver is left unmodified.
+func (t Persistent) DeleteMax() (result Persistent, deleted Item) {
+	result = t
+	result.root, deleted = pDeleteMax(t.root)
+	if result.root != nil {
+		result.root.Black = true
+	}
+	if deleted != nil {
+		result.count--
+	}
+	return result, deleted
+}
+
+func pDeleteMax(h *Node) (*Node, Item) {
+	if h == nil {
+		return nil, nil
+	}
+	h = cloneNode(h)
+	if isRed(h.Left) {
+		h = pRotateRight(h)
+	}
+	if h.Right == nil {
+		return nil, h.Item
+	}
+	if !isRed(h.Right) && !isRed(h.Right.Left) {
+		h = pMoveRedRight(h)
+	}
+	var deleted Item
+	h.Right, deleted = pDeleteMax(h.Right)
+	if deleted != nil {
+		h.NRight--
+	}
+
+	return pFixUp(h), deleted
+}
+
+// Delete returns a new tree with the element whose key equals key
+// removed, along with the deleted item, if any, and its position from
+// the smallest item in the tree. The receiver is left unmodified.
+func (t Persistent) Delete(key Item) (result Persistent, deleted Item, pos int) {
+	result = t
+	result.root, deleted, pos = result.delete(t.root, key, 0)
+	if result.root != nil {
+		result.root.Black = true
+	}
+	if deleted != nil {
+		result.count--
+	}
+	return result, deleted, pos
+}
+
+func (t Persistent) delete(h *Node, item Item, n int) (*Node, Item, int) {
+	if h == nil {
+		return nil, nil, n
+	}
+	h = cloneNode(h)
+
+	var deleted Item
+	var pos int
+	if less(item, h.Item) {
+		if h.Left == nil { // item not present. Nothing to delete
+			return h, nil, -1
+		}
+		if !isRed(h.Left) && !isRed(h.Left.Left) {
+			h = pMoveRedLeft(h)
+		}
+		h.Left, deleted, pos = t.delete(h.Left, item, n)
+		if deleted != nil {
+			h.NLeft--
+		}
+	} else {
+		if isRed(h.Left) {
+			h = pRotateRight(h)
+		}
+		if !less(h.Item, item) && h.Right == nil {
+			return nil, h.Item, n + h.NLeft
+		}
+		if h.Right != nil && !isRed(h.Right) && !isRed(h.Right.Left) {
+			h = pMoveRedRight(h)
+		}
+		if !less(h.Item, item) {
+			var subDeleted Item
+			h.Right, subDeleted = pDeleteMin(h.Right)
+			if subDeleted == nil {
+				panic("logic")
+			}
+			deleted, h.Item, pos = h.Item, subDeleted, n+h.NLeft
+		} else {
+			h.Right, deleted, pos = t.delete(h.Right, item, n+1+h.NLeft)
+		}
+		if deleted != nil {
+			h.NRight--
+		}
+	}
+
+	return pFixUp(h), deleted, pos
+}
+
+// Persistent rotation and rebalancing routines. Unlike their LLRB
+// counterparts, these clone every child node whose fields they are about
+// to write; the node h passed in is written directly and must already be
+// a fresh clone. A subtree that was not already cloned on the way down is
+// therefore never mutated in place.
+
+func pRotateLeft(h *Node) *Node {
+	x := cloneNode(h.Right)
+	if x.Black {
+		panic("rotating a black link")
+	}
+	h.Right = x.Left
+	x.Left = h
+	x.Black = h.Black
+	h.Black = false
+
+	h.NRight = h.Right.Len()
+	x.NLeft = x.Left.Len()
+
+	return x
+}
+
+func pRotateRight(h *Node) *Node {
+	x := cloneNode(h.Left)
+	if x.Black {
+		panic("rotating a black link")
+	}
+	h.Left = x.Right
+	x.Right = h
+	x.Black = h.Black
+	h.Black = false
+
+	h.NLeft = h.Left.Len()
+	x.NRight = x.Right.Len()
+
+	return x
+}
+
+// REQUIRE: Left and Right children must be present
+func pFlip(h *Node) {
+	h.Black = !h.Black
+	h.Left = cloneNode(h.Left)
+	h.Left.Black = !h.Left.Black
+	h.Right = cloneNode(h.Right)
+	h.Right.Black = !h.Right.Black
+}
+
+// REQUIRE: Left and Right children must be present
+func pMoveRedLeft(h *Node) *Node {
+	pFlip(h)
+	if isRed(h.Right.Left) {
+		h.Right = pRotateRight(h.Right)
+		h = pRotateLeft(h)
+		pFlip(h)
+	}
+	return h
+}
+
+// REQUIRE: Left and Right children must be present
+func pMoveRedRight(h *Node) *Node {
+	pFlip(h)
+	if isRed(h.Left.Left) {
+		h = pRotateRight(h)
+		pFlip(h)
+	}
+	return h
+}
+
+func pFixUp(h *Node) *Node {
+	if isRed(h.Right) {
+		h = pRotateLeft(h)
+	}
+
+	if isRed(h.Left) && isRed(h.Left.Left) {
+		h = pRotateRight(h)
+	}
+
+	if isRed(h.Left) && isRed(h.Right) {
+		pFlip(h)
+	}
+
+	return h
+}
@@ -0,0 +1,100 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// rankOf walks h the way LLRB.Rank does, returning the number of nodes
+// reachable from h whose item is strictly less than key. It lets tests
+// cross-check NLeft/NRight bookkeeping on a Persistent, which has no
+// Rank method of its own.
+func rankOf(h *Node, key Item) int {
+	rank := 0
+	for h != nil {
+		switch {
+		case less(key, h.Item):
+			h = h.Left
+		case less(h.Item, key):
+			rank += h.NLeft + 1
+			h = h.Right
+		default:
+			return rank + h.NLeft
+		}
+	}
+	return rank
+}
+
+// TestPersistentReplaceOrInsertPositions drives Persistent.ReplaceOrInsert
+// through a randomized sequence of insertions with Debug-style invariant
+// checks after every update (recomputing NLeft/NRight from scratch, and
+// cross-checking rankOf against a reference sorted slice), and also
+// checks the pos returned for brand new items against that reference.
+func TestPersistentReplaceOrInsertPositions(t *testing.T) {
+	var tree Persistent
+	var ref []int
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		v := rng.Intn(300)
+		var pos int
+		tree, _, pos = tree.ReplaceOrInsert(Int(v))
+
+		idx := sort.SearchInts(ref, v)
+		isNew := idx == len(ref) || ref[idx] != v
+		if isNew {
+			ref = append(ref, 0)
+			copy(ref[idx+1:], ref[idx:])
+			ref[idx] = v
+			if pos != idx {
+				t.Fatalf("ReplaceOrInsert(%d) pos = %d, want %d", v, pos, idx)
+			}
+		}
+
+		if tree.Len() != len(ref) {
+			t.Fatalf("Len() = %d, want %d", tree.Len(), len(ref))
+		}
+		checkInvariants(tree.root)
+		for rank, rv := range ref {
+			if got := rankOf(tree.root, Int(rv)); got != rank {
+				t.Fatalf("rankOf(%d) = %d, want %d", rv, got, rank)
+			}
+		}
+	}
+}
+
+// TestPersistentSnapshotImmutability checks that a Persistent value
+// taken by plain copy is unaffected by further updates made from it.
+func TestPersistentSnapshotImmutability(t *testing.T) {
+	var tree Persistent
+	for _, v := range []int{10, 20, 30, 40, 50} {
+		tree, _, _ = tree.ReplaceOrInsert(Int(v))
+	}
+
+	snapshot := tree
+
+	tree, _, _ = tree.ReplaceOrInsert(Int(25))
+	tree, _ = tree.DeleteMin()
+	tree, _, _ = tree.Delete(Int(40))
+
+	for _, v := range []int{10, 20, 30, 40, 50} {
+		if got := snapshot.Get(Int(v)); got != Int(v) {
+			t.Fatalf("snapshot.Get(%d) = %v, want %d (snapshot mutated)", v, got, v)
+		}
+	}
+	if snapshot.Has(Int(25)) {
+		t.Fatalf("snapshot.Has(25) = true, want false (snapshot mutated)")
+	}
+	if snapshot.Len() != 5 {
+		t.Fatalf("snapshot.Len() = %d, want 5", snapshot.Len())
+	}
+
+	if !tree.Has(Int(25)) || tree.Has(Int(10)) || !tree.Has(Int(30)) || tree.Has(Int(40)) {
+		t.Fatalf("updated tree does not reflect its own edits: %v", tree)
+	}
+}
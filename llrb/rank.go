@@ -0,0 +1,67 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+// Rank returns the number of elements in the tree that are strictly
+// less than key.
+func (t *LLRB) Rank(key Item) int {
+	h := t.root
+	rank := 0
+	for h != nil {
+		switch {
+		case less(key, h.Item):
+			h = h.Left
+		case less(h.Item, key):
+			rank += h.NLeft + 1
+			h = h.Right
+		default:
+			return rank + h.NLeft
+		}
+	}
+	return rank
+}
+
+// Select returns the kth smallest element in the tree, 0-indexed. It
+// panics if k is out of range.
+func (t *LLRB) Select(k int) Item {
+	h := t.root
+	for h != nil {
+		switch {
+		case k < h.NLeft:
+			h = h.Left
+		case k == h.NLeft:
+			return h.Item
+		default:
+			k -= h.NLeft + 1
+			h = h.Right
+		}
+	}
+	panic("index out of range")
+}
+
+// DeleteAt deletes the kth smallest element in the tree, 0-indexed, and
+// returns it along with its position (which is always k). It panics if
+// k is out of range.
+func (t *LLRB) DeleteAt(k int) (Item, int) {
+	return t.Delete(t.Select(k))
+}
+
+// checkInvariants recomputes NLeft/NRight for every node reachable from
+// h and panics if the stored counts disagree. The mutating methods of
+// LLRB call it when Debug is true; tests also call it directly.
+func checkInvariants(h *Node) int {
+	if h == nil {
+		return 0
+	}
+	nleft := checkInvariants(h.Left)
+	nright := checkInvariants(h.Right)
+	if h.NLeft != nleft {
+		panic("NLeft invariant violated")
+	}
+	if h.NRight != nright {
+		panic("NRight invariant violated")
+	}
+	return nleft + 1 + nright
+}